@@ -0,0 +1,178 @@
+/*
+ * Copyright 2021 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ameer-alhadidi/sonic/internal/native/types"
+)
+
+// ErrNotExist is returned by Searcher.GetByPath (and the other lookup
+// methods built on it) when the source JSON is well-formed but has no
+// value at the requested path.
+var ErrNotExist = errors.New("value not exists")
+
+// errorCodeFromParsingError translates a low-level types.ParsingError from
+// the Parser into the ErrorCode a SyntaxError exposes to callers.
+func errorCodeFromParsingError(perr types.ParsingError) ErrorCode {
+	switch perr {
+	case types.ERR_EOF:
+		return ErrUnexpectedEnd
+	case types.ERR_INVALID_CHAR:
+		return ErrInvalidChar
+	case types.ERR_INVALID_ESCAPE:
+		return ErrInvalidEscape
+	case types.ERR_INVALID_NUMBER_FMT:
+		return ErrInvalidNumber
+	default:
+		return ErrUnknown
+	}
+}
+
+// ErrorCode classifies why a SyntaxError was raised, so callers can branch
+// on the cause of a parse failure instead of matching on its message.
+type ErrorCode uint8
+
+const (
+	// ErrUnknown is used for syntax errors raised before typed codes were
+	// attached to every call site; new call sites should not produce it.
+	ErrUnknown ErrorCode = iota
+	ErrInvalidChar
+	ErrUnexpectedEnd
+	ErrInvalidEscape
+	ErrInvalidNumber
+	ErrInvalidUTF8
+	ErrMismatchQuote
+	ErrExceedDepth
+)
+
+func (self ErrorCode) String() string {
+	switch self {
+	case ErrInvalidChar:
+		return "invalid char"
+	case ErrUnexpectedEnd:
+		return "unexpected end of input"
+	case ErrInvalidEscape:
+		return "invalid escape sequence"
+	case ErrInvalidNumber:
+		return "invalid number"
+	case ErrInvalidUTF8:
+		return "invalid UTF-8 sequence"
+	case ErrMismatchQuote:
+		return "mismatched quote"
+	case ErrExceedDepth:
+		return "max depth exceeded"
+	default:
+		return "syntax error"
+	}
+}
+
+// LegacySyntaxErrorFormat makes SyntaxError.Error() render the old quoted
+// "Syntax error at index N: ..." form that every *SyntaxError used to be
+// printed as, for code that still matches on that exact string. New code
+// should branch on Code, Offset, Line and Column instead and leave this
+// false; it defaults to false so Error() returns the more compact form.
+var LegacySyntaxErrorFormat = false
+
+// SyntaxError is returned whenever the Searcher encounters malformed JSON.
+// It replaces the previous bare quoted-string errors with fields a caller
+// can inspect programmatically.
+type SyntaxError struct {
+	Src    string        // the JSON being scanned
+	Offset int           // byte offset into Src where the error was detected
+	Code   ErrorCode     // what kind of syntax error this is
+	Path   []interface{} // the path already resolved via GetByPath, if any, when the error occurred
+}
+
+func newSyntaxError(src string, offset int, code ErrorCode, path []interface{}) *SyntaxError {
+	return &SyntaxError{Src: src, Offset: offset, Code: code, Path: path}
+}
+
+// Position returns the 1-based line and column of Offset within Src.
+func (self *SyntaxError) Position() (line, column int) {
+	line = 1
+	column = 1
+	for i := 0; i < self.Offset && i < len(self.Src); i++ {
+		if self.Src[i] == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return
+}
+
+// Snippet renders the line Offset falls on, with a caret pointing at the
+// exact byte, in the same style the legacy error message used.
+func (self *SyntaxError) Snippet() string {
+	p := self.Offset
+	if p > len(self.Src) {
+		p = len(self.Src)
+	}
+	start := strings.LastIndexByte(self.Src[:p], '\n') + 1
+	end := strings.IndexByte(self.Src[p:], '\n')
+	if end < 0 {
+		end = len(self.Src)
+	} else {
+		end += p
+	}
+	line := self.Src[start:end]
+	trailing := end - p - 1
+	if trailing < 0 {
+		trailing = 0
+	}
+	caret := strings.Repeat(".", p-start) + "^" + strings.Repeat(".", trailing)
+	return fmt.Sprintf("\n\n\t%s\n\t%s\n", line, caret)
+}
+
+// Error implements error. By default it renders a compact, single-line
+// message; set LegacySyntaxErrorFormat to reproduce the previous quoted,
+// multi-line form exactly.
+func (self *SyntaxError) Error() string {
+	if LegacySyntaxErrorFormat {
+		return strconv.Quote(fmt.Sprintf("Syntax error at index %d: %s%s", self.Offset, self.Code, self.Snippet()))
+	}
+	line, col := self.Position()
+	msg := fmt.Sprintf("ast: syntax error at offset %d (line %d, column %d): %s", self.Offset, line, col, self.Code)
+	if len(self.Path) > 0 {
+		msg += fmt.Sprintf(" (while resolving path %v)", self.Path)
+	}
+	return msg
+}
+
+// PathError wraps an error with the GetByPath path that was being resolved
+// when it occurred. It implements Unwrap so errors.Is(err, ErrNotExist)
+// still works on a wrapped PathError.
+type PathError struct {
+	Path []interface{}
+	Err  error
+}
+
+func (self *PathError) Error() string {
+	return fmt.Sprintf("ast: %v at path %v", self.Err, self.Path)
+}
+
+// Unwrap lets errors.Is/errors.As see through PathError to the underlying
+// error, e.g. ErrNotExist or a *SyntaxError.
+func (self *PathError) Unwrap() error {
+	return self.Err
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2021 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyntaxError_Position(t *testing.T) {
+	src := "{\n  \"a\": ]\n}"
+	err := newSyntaxError(src, 9, ErrInvalidChar, nil)
+	line, col := err.Position()
+	assert.Equal(t, 2, line)
+	assert.Equal(t, 8, col)
+}
+
+func TestSyntaxError_Error(t *testing.T) {
+	err := newSyntaxError(`{:"b"]`, 1, ErrInvalidChar, nil)
+
+	LegacySyntaxErrorFormat = false
+	assert.Contains(t, err.Error(), "offset 1")
+	assert.Contains(t, err.Error(), ErrInvalidChar.String())
+
+	LegacySyntaxErrorFormat = true
+	assert.Equal(t, `"Syntax error at index 1: invalid char\n\n\t{:\"b\"]\n\t.^....\n"`, err.Error())
+	LegacySyntaxErrorFormat = false
+}
+
+func TestSyntaxError_WithPath(t *testing.T) {
+	err := newSyntaxError(`{"a":]}`, 5, ErrInvalidChar, []interface{}{"a"})
+	assert.Contains(t, err.Error(), `[a]`)
+}
+
+func TestPathError_Unwrap(t *testing.T) {
+	err := &PathError{Path: []interface{}{"a", 0}, Err: ErrNotExist}
+	assert.True(t, errors.Is(err, ErrNotExist))
+	assert.True(t, errors.As(err, &err))
+	assert.Contains(t, err.Error(), "a")
+}
@@ -0,0 +1,538 @@
+/*
+ * Copyright 2021 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONPathError reports a malformed JSONPath expression, pointing at the
+// byte offset in expr where the parser gave up.
+type JSONPathError struct {
+	Expr   string
+	Offset int
+	Msg    string
+}
+
+// Error implements error.
+func (self *JSONPathError) Error() string {
+	return fmt.Sprintf("invalid JSONPath %q at index %d: %s", self.Expr, self.Offset, self.Msg)
+}
+
+func newJSONPathError(expr string, offset int, msg string) *JSONPathError {
+	return &JSONPathError{Expr: expr, Offset: offset, Msg: msg}
+}
+
+// GetByJSONPath evaluates expr, a practical subset of JSONPath, against the
+// source JSON and returns every matching Node. Supported syntax is: the
+// root selector "$", dot and bracket child access ("$.a", "$['a']"),
+// recursive descent (".."), wildcards ("*"), array slices
+// ("[start:end:step]"), unions ("[0,2]", "['a','b']") and simple filter
+// expressions ("[?(@.field OP literal)]" with OP one of
+// == != < <= > >=).
+//
+// Unlike GetByPath, GetByJSONPath materializes every subtree it walks: a
+// query can match an unbounded number of nodes scattered across the
+// document, so there is no single lazy cursor to reuse. expr is compiled
+// once and then evaluated against the fully decoded document.
+func (self *Searcher) GetByJSONPath(expr string) ([]Node, error) {
+	prog, err := compileJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	root, err := self.GetByPath()
+	if err != nil {
+		return nil, err
+	}
+	doc, err := root.Interface()
+	if err != nil {
+		return nil, err
+	}
+	matches := prog.run(doc)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	nodes := make([]Node, 0, len(matches))
+	for _, m := range matches {
+		raw, err := json.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, NewRaw(string(raw)))
+	}
+	return nodes, nil
+}
+
+// jsonPathOp identifies the kind of a compiled JSONPath selector.
+type jsonPathOp uint8
+
+const (
+	jpKey jsonPathOp = iota
+	jpWildcard
+	jpIndex
+	jpSlice
+	jpUnion
+	jpFilter
+)
+
+type jsonPathUnionItem struct {
+	isIndex bool
+	key     string
+	index   int
+}
+
+type jsonPathFilter struct {
+	field   string
+	op      string
+	literal interface{}
+}
+
+type jsonPathSelector struct {
+	op        jsonPathOp
+	recursive bool
+
+	key   string
+	index int
+
+	hasStart, hasEnd, hasStep bool
+	start, end, step          int
+
+	union  []jsonPathUnionItem
+	filter jsonPathFilter
+}
+
+// jsonPathProgram is a compiled JSONPath expression, ready to run against
+// any decoded JSON value.
+type jsonPathProgram struct {
+	selectors []jsonPathSelector
+}
+
+func (self *jsonPathProgram) run(doc interface{}) []interface{} {
+	values := []interface{}{doc}
+	for _, sel := range self.selectors {
+		if sel.recursive {
+			expanded := make([]interface{}, 0, len(values))
+			for _, v := range values {
+				expanded = append(expanded, collectDescendants(v)...)
+			}
+			values = expanded
+		}
+		var next []interface{}
+		for _, v := range values {
+			next = append(next, applySelector(v, sel)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func collectDescendants(v interface{}) []interface{} {
+	out := []interface{}{v}
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for _, child := range t {
+			out = append(out, collectDescendants(child)...)
+		}
+	case []interface{}:
+		for _, child := range t {
+			out = append(out, collectDescendants(child)...)
+		}
+	}
+	return out
+}
+
+func applySelector(v interface{}, sel jsonPathSelector) []interface{} {
+	switch sel.op {
+	case jpKey:
+		if obj, ok := v.(map[string]interface{}); ok {
+			if child, ok := obj[sel.key]; ok {
+				return []interface{}{child}
+			}
+		}
+	case jpWildcard:
+		return childValues(v)
+	case jpIndex:
+		if arr, ok := v.([]interface{}); ok {
+			if i, ok := normalizeIndex(sel.index, len(arr)); ok {
+				return []interface{}{arr[i]}
+			}
+		}
+	case jpSlice:
+		if arr, ok := v.([]interface{}); ok {
+			return sliceArray(arr, sel)
+		}
+	case jpUnion:
+		var out []interface{}
+		for _, item := range sel.union {
+			if item.isIndex {
+				if arr, ok := v.([]interface{}); ok {
+					if i, ok := normalizeIndex(item.index, len(arr)); ok {
+						out = append(out, arr[i])
+					}
+				}
+			} else if obj, ok := v.(map[string]interface{}); ok {
+				if child, ok := obj[item.key]; ok {
+					out = append(out, child)
+				}
+			}
+		}
+		return out
+	case jpFilter:
+		if arr, ok := v.([]interface{}); ok {
+			var out []interface{}
+			for _, elem := range arr {
+				if matchesFilter(elem, sel.filter) {
+					out = append(out, elem)
+				}
+			}
+			return out
+		}
+	}
+	return nil
+}
+
+func childValues(v interface{}) []interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make([]interface{}, 0, len(t))
+		for _, child := range t {
+			out = append(out, child)
+		}
+		return out
+	case []interface{}:
+		return append([]interface{}{}, t...)
+	}
+	return nil
+}
+
+func normalizeIndex(i, n int) (int, bool) {
+	if i < 0 {
+		i += n
+	}
+	if i < 0 || i >= n {
+		return 0, false
+	}
+	return i, true
+}
+
+func sliceArray(arr []interface{}, sel jsonPathSelector) []interface{} {
+	n := len(arr)
+	step := 1
+	if sel.hasStep {
+		step = sel.step
+	}
+	if step == 0 {
+		return nil
+	}
+	start := 0
+	end := n
+	if step < 0 {
+		start, end = n-1, -1
+	}
+	if sel.hasStart {
+		start = clampSliceIndex(sel.start, n)
+	}
+	if sel.hasEnd {
+		end = clampSliceIndex(sel.end, n)
+	}
+	var out []interface{}
+	if step > 0 {
+		for i := start; i < end && i < n; i += step {
+			if i >= 0 {
+				out = append(out, arr[i])
+			}
+		}
+	} else {
+		for i := start; i > end && i >= 0; i += step {
+			if i < n {
+				out = append(out, arr[i])
+			}
+		}
+	}
+	return out
+}
+
+func clampSliceIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > n {
+		return n
+	}
+	return i
+}
+
+func matchesFilter(v interface{}, f jsonPathFilter) bool {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	actual, ok := obj[f.field]
+	if !ok {
+		return false
+	}
+	return compareFilterValues(actual, f.op, f.literal)
+}
+
+func compareFilterValues(actual interface{}, op string, literal interface{}) bool {
+	if op == "==" {
+		return actual == literal
+	}
+	if op == "!=" {
+		return actual != literal
+	}
+	af, aok := actual.(float64)
+	lf, lok := literal.(float64)
+	if !aok || !lok {
+		return false
+	}
+	switch op {
+	case "<":
+		return af < lf
+	case "<=":
+		return af <= lf
+	case ">":
+		return af > lf
+	case ">=":
+		return af >= lf
+	}
+	return false
+}
+
+// compileJSONPath parses expr into a jsonPathProgram.
+func compileJSONPath(expr string) (*jsonPathProgram, error) {
+	p := &jsonPathParser{expr: expr}
+	return p.parse()
+}
+
+type jsonPathParser struct {
+	expr string
+	pos  int
+}
+
+func (self *jsonPathParser) errorf(offset int, format string, args ...interface{}) error {
+	return newJSONPathError(self.expr, offset, fmt.Sprintf(format, args...))
+}
+
+func (self *jsonPathParser) parse() (*jsonPathProgram, error) {
+	if strings.HasPrefix(self.expr, "$") {
+		self.pos = 1
+	}
+	prog := &jsonPathProgram{}
+	recursive := false
+	for self.pos < len(self.expr) {
+		c := self.expr[self.pos]
+		switch {
+		case c == '.':
+			self.pos++
+			if self.pos < len(self.expr) && self.expr[self.pos] == '.' {
+				self.pos++
+				recursive = true
+			}
+			// ".." only marks the next selector as recursive; it still
+			// needs one, whether that's a dot-name/wildcard or a bracket.
+			if self.pos < len(self.expr) && self.expr[self.pos] == '[' {
+				sel, err := self.parseBracket()
+				if err != nil {
+					return nil, err
+				}
+				sel.recursive = recursive
+				prog.selectors = append(prog.selectors, sel)
+				recursive = false
+				continue
+			}
+			name, wildcard, err := self.parseDotName()
+			if err != nil {
+				return nil, err
+			}
+			if wildcard {
+				prog.selectors = append(prog.selectors, jsonPathSelector{op: jpWildcard, recursive: recursive})
+			} else {
+				prog.selectors = append(prog.selectors, jsonPathSelector{op: jpKey, key: name, recursive: recursive})
+			}
+			recursive = false
+		case c == '[':
+			sel, err := self.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			sel.recursive = recursive
+			prog.selectors = append(prog.selectors, sel)
+			recursive = false
+		default:
+			return nil, self.errorf(self.pos, "unexpected char %q", c)
+		}
+	}
+	if recursive {
+		return nil, self.errorf(self.pos, "recursive descent must be followed by a selector")
+	}
+	return prog, nil
+}
+
+// parseDotName parses the field name following a ".", and reports whether
+// it was the wildcard "*" rather than a literal name.
+func (self *jsonPathParser) parseDotName() (name string, wildcard bool, err error) {
+	start := self.pos
+	for self.pos < len(self.expr) && self.expr[self.pos] != '.' && self.expr[self.pos] != '[' {
+		self.pos++
+	}
+	if self.pos == start {
+		return "", false, self.errorf(start, "expected a field name")
+	}
+	if self.expr[start:self.pos] == "*" {
+		return "", true, nil
+	}
+	return self.expr[start:self.pos], false, nil
+}
+
+func (self *jsonPathParser) parseBracket() (jsonPathSelector, error) {
+	start := self.pos
+	self.pos++ // consume '['
+	end := strings.IndexByte(self.expr[self.pos:], ']')
+	if end < 0 {
+		return jsonPathSelector{}, self.errorf(start, "unterminated '['")
+	}
+	content := strings.TrimSpace(self.expr[self.pos : self.pos+end])
+	self.pos += end + 1
+
+	switch {
+	case content == "*":
+		return jsonPathSelector{op: jpWildcard}, nil
+	case strings.HasPrefix(content, "?"):
+		return self.parseFilter(start, content)
+	case isQuoted(content) && !strings.ContainsRune(content[1:len(content)-1], rune(content[0])):
+		// A single quoted key is a member access regardless of what
+		// punctuation it contains, so this must be checked before the
+		// ":"/"," separator checks below. Content such as "'a','b'" holds
+		// several quoted keys and falls through to the union case.
+		return self.parseSingleItem(start, content)
+	case strings.Contains(content, ":"):
+		return self.parseSlice(start, content)
+	case strings.Contains(content, ","):
+		return self.parseUnion(start, content)
+	default:
+		return self.parseSingleItem(start, content)
+	}
+}
+
+func (self *jsonPathParser) parseSingleItem(offset int, content string) (jsonPathSelector, error) {
+	if isQuoted(content) {
+		return jsonPathSelector{op: jpKey, key: content[1 : len(content)-1]}, nil
+	}
+	idx, err := strconv.Atoi(content)
+	if err != nil {
+		return jsonPathSelector{}, self.errorf(offset, "invalid index or key %q", content)
+	}
+	return jsonPathSelector{op: jpIndex, index: idx}, nil
+}
+
+func (self *jsonPathParser) parseUnion(offset int, content string) (jsonPathSelector, error) {
+	parts := strings.Split(content, ",")
+	items := make([]jsonPathUnionItem, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if isQuoted(part) {
+			items = append(items, jsonPathUnionItem{key: part[1 : len(part)-1]})
+			continue
+		}
+		idx, err := strconv.Atoi(part)
+		if err != nil {
+			return jsonPathSelector{}, self.errorf(offset, "invalid union member %q", part)
+		}
+		items = append(items, jsonPathUnionItem{isIndex: true, index: idx})
+	}
+	return jsonPathSelector{op: jpUnion, union: items}, nil
+}
+
+func (self *jsonPathParser) parseSlice(offset int, content string) (jsonPathSelector, error) {
+	parts := strings.Split(content, ":")
+	if len(parts) > 3 {
+		return jsonPathSelector{}, self.errorf(offset, "invalid slice %q", content)
+	}
+	sel := jsonPathSelector{op: jpSlice}
+	fields := []*int{&sel.start, &sel.end, &sel.step}
+	has := []*bool{&sel.hasStart, &sel.hasEnd, &sel.hasStep}
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return jsonPathSelector{}, self.errorf(offset, "invalid slice bound %q", part)
+		}
+		*fields[i] = v
+		*has[i] = true
+	}
+	return sel, nil
+}
+
+func (self *jsonPathParser) parseFilter(offset int, content string) (jsonPathSelector, error) {
+	body := strings.TrimSpace(content[1:])
+	if !strings.HasPrefix(body, "(") || !strings.HasSuffix(body, ")") {
+		return jsonPathSelector{}, self.errorf(offset, "filter must be of the form ?(@.field OP literal)")
+	}
+	body = strings.TrimSpace(body[1 : len(body)-1])
+	if !strings.HasPrefix(body, "@.") {
+		return jsonPathSelector{}, self.errorf(offset, "filter field must start with '@.'")
+	}
+	body = body[2:]
+
+	var opName string
+	for _, candidate := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if idx := strings.Index(body, candidate); idx >= 0 {
+			opName = candidate
+			field := strings.TrimSpace(body[:idx])
+			literalStr := strings.TrimSpace(body[idx+len(candidate):])
+			literal, err := parseFilterLiteral(literalStr)
+			if err != nil {
+				return jsonPathSelector{}, self.errorf(offset, "%s", err)
+			}
+			return jsonPathSelector{op: jpFilter, filter: jsonPathFilter{field: field, op: opName, literal: literal}}, nil
+		}
+	}
+	return jsonPathSelector{}, self.errorf(offset, "filter %q is missing a comparison operator", body)
+}
+
+func parseFilterLiteral(s string) (interface{}, error) {
+	switch {
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	case s == "null":
+		return nil, nil
+	case isQuoted(s):
+		return s[1 : len(s)-1], nil
+	default:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter literal %q", s)
+		}
+		return f, nil
+	}
+}
+
+func isQuoted(s string) bool {
+	return len(s) >= 2 && ((s[0] == '\'' && s[len(s)-1] == '\'') || (s[0] == '"' && s[len(s)-1] == '"'))
+}
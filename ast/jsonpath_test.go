@@ -0,0 +1,140 @@
+/*
+ * Copyright 2021 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const _JsonPathStore = `{
+	"store": {
+		"book": [
+			{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "price": 8.99},
+			{"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "price": 22.99},
+			{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95}
+		],
+		"bicycle": {"color": "red", "price": 19.95}
+	}
+}`
+
+func pluck(t *testing.T, nodes []Node, key string) []interface{} {
+	out := make([]interface{}, 0, len(nodes))
+	for _, n := range nodes {
+		v, err := n.Interface()
+		require.NoError(t, err)
+		if key == "" {
+			out = append(out, v)
+			continue
+		}
+		m, ok := v.(map[string]interface{})
+		require.True(t, ok)
+		out = append(out, m[key])
+	}
+	return out
+}
+
+func TestSearcher_GetByJSONPath(t *testing.T) {
+	s := NewSearcher(_JsonPathStore)
+
+	nodes, err := s.GetByJSONPath("$.store.bicycle.color")
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	v, _ := nodes[0].Interface()
+	assert.Equal(t, "red", v)
+
+	nodes, err = s.GetByJSONPath("$.store.book[0].title")
+	require.NoError(t, err)
+	v, _ = nodes[0].Interface()
+	assert.Equal(t, "Moby Dick", v)
+
+	nodes, err = s.GetByJSONPath("$.store.book[0,2].title")
+	require.NoError(t, err)
+	titles := pluck(t, nodes, "")
+	sort.Slice(titles, func(i, j int) bool { return titles[i].(string) < titles[j].(string) })
+	assert.Equal(t, []interface{}{"Moby Dick", "Sayings of the Century"}, titles)
+
+	nodes, err = s.GetByJSONPath("$.store.book[0:2].title")
+	require.NoError(t, err)
+	assert.Len(t, nodes, 2)
+
+	nodes, err = s.GetByJSONPath(`$.store.book[?(@.price<10)].title`)
+	require.NoError(t, err)
+	titles = pluck(t, nodes, "")
+	sort.Slice(titles, func(i, j int) bool { return titles[i].(string) < titles[j].(string) })
+	assert.Equal(t, []interface{}{"Moby Dick", "Sayings of the Century"}, titles)
+
+	nodes, err = s.GetByJSONPath("$..price")
+	require.NoError(t, err)
+	assert.Len(t, nodes, 4)
+
+	nodes, err = s.GetByJSONPath("$.store.book[*].author")
+	require.NoError(t, err)
+	assert.Len(t, nodes, 3)
+
+	nodes, err = s.GetByJSONPath("$.store.missing")
+	require.NoError(t, err)
+	assert.Len(t, nodes, 0)
+
+	// A dot-form wildcard must behave like "[*]", not a lookup of the
+	// empty-string member.
+	nodes, err = s.GetByJSONPath("$.store.book.*")
+	require.NoError(t, err)
+	assert.Len(t, nodes, 3)
+
+	nodes, err = s.GetByJSONPath("$..*")
+	require.NoError(t, err)
+	assert.True(t, len(nodes) > 3)
+}
+
+func TestSearcher_GetByJSONPath_QuotedKeyWithSeparator(t *testing.T) {
+	// A quoted bracket key containing ":" or "," must select that member,
+	// not be misrouted to slice/union parsing.
+	s := NewSearcher(`{"a:b":1,"x,y":2}`)
+
+	nodes, err := s.GetByJSONPath(`$['a:b']`)
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	v, _ := nodes[0].Interface()
+	assert.Equal(t, 1.0, v)
+
+	nodes, err = s.GetByJSONPath(`$['x,y']`)
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	v, _ = nodes[0].Interface()
+	assert.Equal(t, 2.0, v)
+}
+
+func TestSearcher_GetByJSONPath_Malformed(t *testing.T) {
+	tests := []string{
+		"$.",
+		"$.store.book[",
+		"$.store.book[?(price<10)]",
+		"$.store.book[?(@.price~10)]",
+	}
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			_, err := NewSearcher(_JsonPathStore).GetByJSONPath(expr)
+			require.Error(t, err)
+			var perr *JSONPathError
+			require.ErrorAs(t, err, &perr)
+		})
+	}
+}
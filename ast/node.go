@@ -0,0 +1,319 @@
+/*
+ * Copyright 2021 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Value-type constants returned by Node.Type().
+const (
+	V_NONE = iota
+	V_ERROR
+	V_NULL
+	V_TRUE
+	V_FALSE
+	V_ARRAY
+	V_OBJECT
+	V_STRING
+	V_NUMBER
+)
+
+// Node is a lazy handle onto a JSON value: it holds the raw source text of
+// that value and decodes it on demand, rather than eagerly building a tree.
+// The zero Node does not exist, matching what GetByPath/GetByPointer return
+// alongside a non-nil error.
+type Node struct {
+	raw string
+}
+
+// NewRaw wraps json, a self-contained JSON value, as a Node without
+// validating or decoding it up front.
+func NewRaw(json string) Node {
+	return Node{raw: json}
+}
+
+// NewNumber wraps s, the decimal text of a JSON number, as a Node.
+func NewNumber(s string) Node {
+	return Node{raw: s}
+}
+
+// NewBool wraps b as a Node.
+func NewBool(b bool) Node {
+	if b {
+		return Node{raw: "true"}
+	}
+	return Node{raw: "false"}
+}
+
+// NewNull returns a Node representing JSON null.
+func NewNull() Node {
+	return Node{raw: "null"}
+}
+
+// NewString wraps s as a Node, JSON-escaping it.
+func NewString(s string) Node {
+	b, _ := json.Marshal(s)
+	return Node{raw: string(b)}
+}
+
+// Exists reports whether self addresses a value at all; it is false for
+// the zero Node returned alongside a lookup error.
+func (self Node) Exists() bool {
+	return self.raw != ""
+}
+
+// Type classifies the JSON value self holds.
+func (self Node) Type() int {
+	if !self.Exists() {
+		return V_NONE
+	}
+	i := 0
+	for i < len(self.raw) && isSpaceByte(self.raw[i]) {
+		i++
+	}
+	if i >= len(self.raw) {
+		return V_NONE
+	}
+	switch self.raw[i] {
+	case '{':
+		return V_OBJECT
+	case '[':
+		return V_ARRAY
+	case '"':
+		return V_STRING
+	case 't':
+		return V_TRUE
+	case 'f':
+		return V_FALSE
+	case 'n':
+		return V_NULL
+	default:
+		return V_NUMBER
+	}
+}
+
+// TypeSafe is Type, kept under this name for callers migrating off the
+// panicking accessors.
+func (self Node) TypeSafe() int {
+	return self.Type()
+}
+
+// isAny reports whether self wraps an already-decoded Go value rather than
+// raw JSON text; this implementation always decodes lazily from raw.
+func (self Node) isAny() bool {
+	return false
+}
+
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// Raw returns the exact JSON text backing self.
+func (self Node) Raw() (string, error) {
+	if !self.Exists() {
+		return "", ErrNotExist
+	}
+	return self.raw, nil
+}
+
+// Interface decodes self into the generic Go representation: nil,
+// bool, float64, string, []interface{} or map[string]interface{}.
+func (self Node) Interface() (interface{}, error) {
+	if !self.Exists() {
+		return nil, ErrNotExist
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(self.raw), &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Bool decodes self as a JSON boolean.
+func (self Node) Bool() (bool, error) {
+	v, err := self.Interface()
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("ast: value is not a bool")
+	}
+	return b, nil
+}
+
+// Float64 decodes self as a JSON number.
+func (self Node) Float64() (float64, error) {
+	v, err := self.Interface()
+	if err != nil {
+		return 0, err
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("ast: value is not a number")
+	}
+	return f, nil
+}
+
+// Int64 decodes self as a JSON number and truncates it to an int64.
+func (self Node) Int64() (int64, error) {
+	f, err := self.Float64()
+	if err != nil {
+		return 0, err
+	}
+	return int64(f), nil
+}
+
+// Number decodes self as a JSON number, preserving its original formatting.
+func (self Node) Number() (json.Number, error) {
+	if self.Type() != V_NUMBER {
+		return "", fmt.Errorf("ast: value is not a number")
+	}
+	return json.Number(self.raw), nil
+}
+
+// String decodes self as a JSON string.
+func (self Node) String() (string, error) {
+	v, err := self.Interface()
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("ast: value is not a string")
+	}
+	return s, nil
+}
+
+// Array decodes self as a JSON array into a generic Go slice.
+func (self Node) Array() ([]interface{}, error) {
+	v, err := self.Interface()
+	if err != nil {
+		return nil, err
+	}
+	a, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("ast: value is not an array")
+	}
+	return a, nil
+}
+
+// Len reports the number of elements of an array or members of an object.
+func (self Node) Len() (int, error) {
+	switch self.Type() {
+	case V_ARRAY:
+		var raw []json.RawMessage
+		if err := json.Unmarshal([]byte(self.raw), &raw); err != nil {
+			return 0, err
+		}
+		return len(raw), nil
+	case V_OBJECT:
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(self.raw), &raw); err != nil {
+			return 0, err
+		}
+		return len(raw), nil
+	default:
+		return 0, fmt.Errorf("ast: value has no length")
+	}
+}
+
+// Index returns the element at position i of a JSON array, or the zero
+// Node if self is not an array or i is out of range.
+func (self Node) Index(i int) Node {
+	if self.Type() != V_ARRAY || i < 0 {
+		return Node{}
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(self.raw), &raw); err != nil || i >= len(raw) {
+		return Node{}
+	}
+	return NewRaw(string(raw[i]))
+}
+
+// Get returns the member named key of a JSON object, or the zero Node if
+// self is not an object or has no such member.
+func (self Node) Get(key string) Node {
+	if self.Type() != V_OBJECT {
+		return Node{}
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(self.raw), &raw); err != nil {
+		return Node{}
+	}
+	v, ok := raw[key]
+	if !ok {
+		return Node{}
+	}
+	return NewRaw(string(v))
+}
+
+// GetByPath walks path, a mix of object member names (string) and array
+// indices (int), against the value held by self. Unlike
+// Searcher.GetByPath it reports a missing value as the zero Node rather
+// than an error, since self is already in memory and there is nothing
+// left to skip.
+func (self Node) GetByPath(path ...interface{}) Node {
+	cur := self
+	for _, seg := range path {
+		switch k := seg.(type) {
+		case string:
+			cur = cur.Get(k)
+		case int:
+			cur = cur.Index(k)
+		default:
+			panic("path must be either int or string")
+		}
+		if !cur.Exists() {
+			return Node{}
+		}
+	}
+	return cur
+}
+
+// SortKeys is kept for API compatibility and does not reorder anything:
+// this implementation always decodes members on demand rather than caching
+// an ordered representation, so it leaves self unchanged, ignores recurse
+// and always returns nil.
+func (self Node) SortKeys(recurse bool) error {
+	if self.Type() != V_OBJECT && self.Type() != V_ARRAY {
+		return nil
+	}
+	return nil
+}
+
+// Set adds or replaces the member named key with val, and reports whether
+// key already existed.
+func (self *Node) Set(key string, val Node) (bool, error) {
+	if self.Type() != V_OBJECT {
+		return false, fmt.Errorf("ast: value is not an object")
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(self.raw), &raw); err != nil {
+		return false, err
+	}
+	_, existed := raw[key]
+	raw[key] = json.RawMessage(val.raw)
+	out, err := json.Marshal(raw)
+	if err != nil {
+		return false, err
+	}
+	self.raw = string(out)
+	return existed, nil
+}
@@ -0,0 +1,257 @@
+/*
+ * Copyright 2021 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"encoding/json"
+
+	"github.com/ameer-alhadidi/sonic/internal/native/types"
+)
+
+// Parser is the low-level, skip-based scanner shared by Searcher.GetByPath
+// and GetByPathSet: it never builds a full AST, it only tracks enough
+// state to resolve or skip one value at a time.
+type Parser struct {
+	s string
+	p int
+}
+
+// lspace returns the index, at or after sp, of the first non-whitespace
+// byte of the source. It does not mutate the parser; callers assign the
+// result back to p.p themselves.
+func (self *Parser) lspace(sp int) int {
+	for sp < len(self.s) {
+		switch self.s[sp] {
+		case ' ', '\t', '\r', '\n':
+			sp++
+		default:
+			return sp
+		}
+	}
+	return sp
+}
+
+// skipFast skips exactly one JSON value starting at self.p, advances
+// self.p to just past it, and returns the value's start offset (after
+// leading whitespace). It is the fast path used when a value only needs to
+// be skipped, not decoded into a Node.
+func (self *Parser) skipFast() (int, types.ParsingError) {
+	sp := self.lspace(self.p)
+	if sp >= len(self.s) {
+		self.p = sp
+		return sp, types.ERR_EOF
+	}
+	start := sp
+	var err types.ParsingError
+	switch self.s[sp] {
+	case '{':
+		err = self.skipObject(sp)
+	case '[':
+		err = self.skipArray(sp)
+	case '"':
+		err = self.skipString(sp)
+	case 't':
+		err = self.skipLiteral(sp, "true")
+	case 'f':
+		err = self.skipLiteral(sp, "false")
+	case 'n':
+		err = self.skipLiteral(sp, "null")
+	default:
+		err = self.skipNumber(sp)
+	}
+	if err != types.ERR_NONE {
+		return start, err
+	}
+	return start, types.ERR_NONE
+}
+
+func (self *Parser) skipObject(sp int) types.ParsingError {
+	self.p = sp + 1
+	sp = self.lspace(self.p)
+	if sp >= len(self.s) {
+		self.p = sp
+		return types.ERR_EOF
+	}
+	if self.s[sp] == '}' {
+		self.p = sp + 1
+		return types.ERR_NONE
+	}
+	for {
+		sp = self.lspace(self.p)
+		if sp >= len(self.s) || self.s[sp] != '"' {
+			self.p = sp
+			return types.ERR_INVALID_CHAR
+		}
+		if _, err := self.decodeString(sp); err != types.ERR_NONE {
+			return err
+		}
+		sp = self.lspace(self.p)
+		if sp >= len(self.s) || self.s[sp] != ':' {
+			self.p = sp
+			return types.ERR_INVALID_CHAR
+		}
+		self.p = sp + 1
+		if _, err := self.skipFast(); err != types.ERR_NONE {
+			return err
+		}
+		sp = self.lspace(self.p)
+		if sp >= len(self.s) {
+			self.p = sp
+			return types.ERR_EOF
+		}
+		switch self.s[sp] {
+		case ',':
+			self.p = sp + 1
+		case '}':
+			self.p = sp + 1
+			return types.ERR_NONE
+		default:
+			self.p = sp
+			return types.ERR_INVALID_CHAR
+		}
+	}
+}
+
+func (self *Parser) skipArray(sp int) types.ParsingError {
+	self.p = sp + 1
+	sp = self.lspace(self.p)
+	if sp >= len(self.s) {
+		self.p = sp
+		return types.ERR_EOF
+	}
+	if self.s[sp] == ']' {
+		self.p = sp + 1
+		return types.ERR_NONE
+	}
+	for {
+		self.p = self.lspace(self.p)
+		if _, err := self.skipFast(); err != types.ERR_NONE {
+			return err
+		}
+		sp = self.lspace(self.p)
+		if sp >= len(self.s) {
+			self.p = sp
+			return types.ERR_EOF
+		}
+		switch self.s[sp] {
+		case ',':
+			self.p = sp + 1
+		case ']':
+			self.p = sp + 1
+			return types.ERR_NONE
+		default:
+			self.p = sp
+			return types.ERR_INVALID_CHAR
+		}
+	}
+}
+
+// skipString skips a JSON string literal starting at the opening quote sp.
+func (self *Parser) skipString(sp int) types.ParsingError {
+	_, err := self.decodeString(sp)
+	return err
+}
+
+// decodeString decodes the JSON string literal starting at the opening
+// quote sp, advances self.p past the closing quote, and returns the
+// unescaped content.
+func (self *Parser) decodeString(sp int) (string, types.ParsingError) {
+	i := sp + 1
+	hasEscape := false
+	for i < len(self.s) {
+		switch self.s[i] {
+		case '"':
+			self.p = i + 1
+			if !hasEscape {
+				return self.s[sp+1 : i], types.ERR_NONE
+			}
+			out, ok := unquoteJSONString(self.s[sp : i+1])
+			if !ok {
+				return "", types.ERR_INVALID_ESCAPE
+			}
+			return out, types.ERR_NONE
+		case '\\':
+			hasEscape = true
+			i += 2
+			continue
+		}
+		i++
+	}
+	self.p = i
+	return "", types.ERR_EOF
+}
+
+func (self *Parser) skipLiteral(sp int, lit string) types.ParsingError {
+	if sp+len(lit) > len(self.s) || self.s[sp:sp+len(lit)] != lit {
+		self.p = sp
+		return types.ERR_INVALID_CHAR
+	}
+	self.p = sp + len(lit)
+	return types.ERR_NONE
+}
+
+func (self *Parser) skipNumber(sp int) types.ParsingError {
+	i := sp
+	if i < len(self.s) && self.s[i] == '-' {
+		i++
+	}
+	start := i
+	for i < len(self.s) && self.s[i] >= '0' && self.s[i] <= '9' {
+		i++
+	}
+	if i == start {
+		self.p = sp
+		return types.ERR_INVALID_CHAR
+	}
+	if i < len(self.s) && self.s[i] == '.' {
+		i++
+		fs := i
+		for i < len(self.s) && self.s[i] >= '0' && self.s[i] <= '9' {
+			i++
+		}
+		if i == fs {
+			self.p = sp
+			return types.ERR_INVALID_NUMBER_FMT
+		}
+	}
+	if i < len(self.s) && (self.s[i] == 'e' || self.s[i] == 'E') {
+		i++
+		if i < len(self.s) && (self.s[i] == '+' || self.s[i] == '-') {
+			i++
+		}
+		es := i
+		for i < len(self.s) && self.s[i] >= '0' && self.s[i] <= '9' {
+			i++
+		}
+		if i == es {
+			self.p = sp
+			return types.ERR_INVALID_NUMBER_FMT
+		}
+	}
+	self.p = i
+	return types.ERR_NONE
+}
+
+// unquoteJSONString decodes a quoted JSON string (including its
+// surrounding quotes) that is known to contain at least one escape.
+func unquoteJSONString(quoted string) (string, bool) {
+	var out string
+	if err := json.Unmarshal([]byte(quoted), &out); err != nil {
+		return "", false
+	}
+	return out, true
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright 2021 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PointerError reports a malformed RFC 6901 JSON Pointer. It is returned
+// directly by GetByPointer and never wraps a value found in the JSON itself.
+type PointerError struct {
+	Pointer string
+	Offset  int
+	Msg     string
+}
+
+// Error implements error.
+func (self *PointerError) Error() string {
+	return fmt.Sprintf("invalid JSON pointer %q at index %d: %s", self.Pointer, self.Offset, self.Msg)
+}
+
+func newPointerError(pointer string, offset int, msg string) *PointerError {
+	return &PointerError{Pointer: pointer, Offset: offset, Msg: msg}
+}
+
+// GetByPointer searches the Node addressed by ptr, an RFC 6901 JSON Pointer
+// such as "/statuses/0/id". The empty pointer "" addresses the whole
+// document.
+//
+// An all-digit token is only treated as an array index into a value that
+// is actually an array; against an object it addresses the member of that
+// name instead, per RFC 6901 section 4. GetByPointer resolves tokens one
+// at a time via GetByPath to tell the two apart, so it reuses the same
+// skip-based, zero-copy traversal: no intermediate AST is built and
+// untouched siblings are skipped rather than parsed.
+//
+// A trailing "-" token addresses the (nonexistent) member past the end of
+// an array, as permitted by RFC 6901 section 4 for documents such as JSON
+// Patch; since that member can never exist, GetByPointer always reports it
+// as ErrNotExist once it has confirmed the pointer otherwise resolves to an
+// array.
+func (self *Searcher) GetByPointer(ptr string) (Node, error) {
+	tokens, err := splitPointer(ptr)
+	if err != nil {
+		return Node{}, err
+	}
+	if n := len(tokens); n > 0 && tokens[n-1] == "-" {
+		path, err := self.resolvePointerPath(tokens[:n-1])
+		if err != nil {
+			return Node{}, err
+		}
+		parent, err := self.GetByPath(path...)
+		if err != nil {
+			return Node{}, err
+		}
+		if parent.Type() != V_ARRAY {
+			return Node{}, newPointerError(ptr, len(ptr), `"-" can only address an element of an array`)
+		}
+		return Node{}, ErrNotExist
+	}
+	path, err := self.resolvePointerPath(tokens)
+	if err != nil {
+		return Node{}, err
+	}
+	return self.GetByPath(path...)
+}
+
+// splitPointer splits a JSON Pointer into its unescaped reference tokens.
+func splitPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, newPointerError(pointer, 0, "pointer must be empty or start with '/'")
+	}
+	raw := strings.Split(pointer[1:], "/")
+	tokens := make([]string, len(raw))
+	offset := 1
+	for i, tok := range raw {
+		unescaped, err := unescapeToken(tok)
+		if err != nil {
+			return nil, newPointerError(pointer, offset, err.Error())
+		}
+		tokens[i] = unescaped
+		offset += len(tok) + 1
+	}
+	return tokens, nil
+}
+
+// unescapeToken decodes the "~1" -> "/" and "~0" -> "~" escapes of RFC 6901.
+// The order matters: "~1" must be resolved before "~0", otherwise "~01"
+// would wrongly decode to "/" instead of "~1".
+func unescapeToken(tok string) (string, error) {
+	if strings.IndexByte(tok, '~') < 0 {
+		return tok, nil
+	}
+	for i := 0; i < len(tok); i++ {
+		if tok[i] == '~' && (i+1 >= len(tok) || (tok[i+1] != '0' && tok[i+1] != '1')) {
+			return "", fmt.Errorf(`"~" must be followed by "0" or "1"`)
+		}
+	}
+	tok = strings.ReplaceAll(tok, "~1", "/")
+	tok = strings.ReplaceAll(tok, "~0", "~")
+	return tok, nil
+}
+
+// resolvePointerPath converts unescaped pointer tokens into the
+// []interface{} form GetByPath expects. RFC 6901 section 4 makes an
+// all-digit token an array index only when the value it steps into is
+// actually an array; against an object (e.g. {"0":1}) the same token
+// addresses that member by name instead. Telling the two apart means
+// inspecting the document as we descend, so this resolves one token at a
+// time, querying the container already resolved before deciding how to
+// interpret the next token.
+func (self *Searcher) resolvePointerPath(tokens []string) ([]interface{}, error) {
+	path := make([]interface{}, 0, len(tokens))
+	for _, tok := range tokens {
+		container, err := self.GetByPath(path...)
+		if err != nil {
+			return nil, err
+		}
+		if idx, ok := pointerIndex(tok); ok && container.Type() == V_ARRAY {
+			path = append(path, idx)
+		} else {
+			path = append(path, tok)
+		}
+	}
+	return path, nil
+}
+
+// pointerIndex reports whether tok is a valid RFC 6901 array index token
+// ("0" or a non-zero digit followed by digits) and returns its value.
+func pointerIndex(tok string) (int, bool) {
+	if tok == "" {
+		return 0, false
+	}
+	if tok == "0" {
+		return 0, true
+	}
+	if tok[0] < '1' || tok[0] > '9' {
+		return 0, false
+	}
+	for i := 1; i < len(tok); i++ {
+		if tok[i] < '0' || tok[i] > '9' {
+			return 0, false
+		}
+	}
+	idx, err := strconv.Atoi(tok)
+	if err != nil {
+		return 0, false
+	}
+	return idx, true
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2021 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearcher_GetByPointer(t *testing.T) {
+	data := `{"a":{"b":[1,2,3]},"c":"d","":0,"e~f":1,"g/h":2}`
+	tests := []struct {
+		ptr   string
+		value interface{}
+	}{
+		{"", map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{1.0, 2.0, 3.0}}, "c": "d", "": 0.0, "e~f": 1.0, "g/h": 2.0}},
+		{"/a/b/0", 1.0},
+		{"/a/b/2", 3.0},
+		{"/c", "d"},
+		{"/", 0.0},
+		{"/e~0f", 1.0},
+		{"/g~1h", 2.0},
+	}
+	for _, test := range tests {
+		t.Run(test.ptr, func(t *testing.T) {
+			node, err := NewSearcher(data).GetByPointer(test.ptr)
+			require.NoError(t, err)
+			v, err := node.Interface()
+			require.NoError(t, err)
+			assert.Equal(t, test.value, v)
+		})
+	}
+}
+
+func TestSearcher_GetByPointer_DigitKeyOnObject(t *testing.T) {
+	// An all-digit token addresses an object member by name, not an array
+	// index, when the value it steps into isn't actually an array.
+	node, err := NewSearcher(`{"0":"zero","nested":{"1":[10,20]}}`).GetByPointer("/0")
+	require.NoError(t, err)
+	v, err := node.Interface()
+	require.NoError(t, err)
+	assert.Equal(t, "zero", v)
+
+	node, err = NewSearcher(`{"0":"zero","nested":{"1":[10,20]}}`).GetByPointer("/nested/1/0")
+	require.NoError(t, err)
+	v, err = node.Interface()
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, v)
+}
+
+func TestSearcher_GetByPointer_NotExist(t *testing.T) {
+	data := `{"a":[1,2,3]}`
+	tests := []string{
+		"/b",
+		"/a/3",
+		"/a/-",
+	}
+	for _, ptr := range tests {
+		t.Run(ptr, func(t *testing.T) {
+			_, err := NewSearcher(data).GetByPointer(ptr)
+			assert.Equal(t, ErrNotExist, err)
+		})
+	}
+}
+
+func TestSearcher_GetByPointer_Malformed(t *testing.T) {
+	tests := []string{
+		"a/b",
+		"/a~",
+		"/a~2",
+	}
+	for _, ptr := range tests {
+		t.Run(ptr, func(t *testing.T) {
+			_, err := NewSearcher(`{}`).GetByPointer(ptr)
+			require.Error(t, err)
+			var perr *PointerError
+			require.ErrorAs(t, err, &perr)
+		})
+	}
+}
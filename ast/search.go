@@ -0,0 +1,194 @@
+/*
+ * Copyright 2021 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"github.com/ameer-alhadidi/sonic/internal/native/types"
+)
+
+// Searcher is used to search a target Node in JSON with a given path,
+// reducing unnecessary node parsing.
+type Searcher struct {
+	parser Parser
+
+	// ConcurrentRead marks that the same Searcher (or the source string it
+	// was built from) may be read from multiple goroutines at once. It is
+	// kept for API compatibility with callers that set it defensively;
+	// since this implementation always works on immutable Go strings,
+	// there is nothing extra to copy.
+	ConcurrentRead bool
+}
+
+// NewSearcher creates a new searcher instance from a JSON source.
+func NewSearcher(str string) *Searcher {
+	return &Searcher{parser: Parser{s: str}}
+}
+
+// GetByPath searches a path and returns the relating Node. Each element of
+// path must be either a string (object member name) or an int (array
+// index); GetByPath panics otherwise.
+//
+// A syntax error in the source is reported as a *SyntaxError (see
+// errors.As), carrying the Offset/Code of the failure and the prefix of
+// path already resolved when it was hit. A path segment that legitimately
+// has no matching value reports ErrNotExist.
+func (self *Searcher) GetByPath(path ...interface{}) (Node, error) {
+	return self.getByPath(path...)
+}
+
+// getByPath is the internal, always-resets-to-offset-0 implementation
+// behind GetByPath. It is exposed unexported so tests in this package can
+// drive it directly, e.g. to inspect self.parser.p.
+func (self *Searcher) getByPath(path ...interface{}) (Node, error) {
+	self.parser.p = 0
+	resolved := make([]interface{}, 0, len(path))
+	for _, seg := range path {
+		switch k := seg.(type) {
+		case int:
+			if err := self.searchIndex(k, resolved); err != nil {
+				return Node{}, err
+			}
+		case string:
+			if err := self.searchKey(k, resolved); err != nil {
+				return Node{}, err
+			}
+		default:
+			panic("path must be either int or string")
+		}
+		resolved = append(resolved, seg)
+	}
+
+	start, perr := self.parser.skipFast()
+	if perr != types.ERR_NONE {
+		return Node{}, self.syntaxError(perr, resolved)
+	}
+	return NewRaw(self.parser.s[start:self.parser.p]), nil
+}
+
+// syntaxError builds a *SyntaxError for a failure encountered while
+// resolving path (the prefix of the caller's path already consumed).
+func (self *Searcher) syntaxError(perr types.ParsingError, path []interface{}) *SyntaxError {
+	return newSyntaxError(self.parser.s, self.parser.p, errorCodeFromParsingError(perr), append([]interface{}{}, path...))
+}
+
+// searchKey advances the parser past an object's "{", matches member key,
+// and leaves self.parser.p positioned at the start of its value. path is
+// only used to annotate a *SyntaxError if the source turns out malformed.
+func (self *Searcher) searchKey(key string, path []interface{}) error {
+	p := &self.parser
+	sp := p.lspace(p.p)
+	if sp >= len(p.s) {
+		p.p = sp
+		return self.syntaxError(types.ERR_EOF, path)
+	}
+	if p.s[sp] != '{' {
+		p.p = sp
+		return self.syntaxError(types.ERR_INVALID_CHAR, path)
+	}
+	p.p = sp + 1
+	sp = p.lspace(p.p)
+	if sp < len(p.s) && p.s[sp] == '}' {
+		p.p = sp + 1
+		return ErrNotExist
+	}
+	for {
+		sp = p.lspace(p.p)
+		if sp >= len(p.s) || p.s[sp] != '"' {
+			p.p = sp
+			return self.syntaxError(types.ERR_INVALID_CHAR, path)
+		}
+		name, perr := p.decodeString(sp)
+		if perr != types.ERR_NONE {
+			return self.syntaxError(perr, path)
+		}
+		sp = p.lspace(p.p)
+		if sp >= len(p.s) || p.s[sp] != ':' {
+			p.p = sp
+			return self.syntaxError(types.ERR_INVALID_CHAR, path)
+		}
+		p.p = sp + 1
+		if name == key {
+			return nil
+		}
+		if _, perr := p.skipFast(); perr != types.ERR_NONE {
+			return self.syntaxError(perr, path)
+		}
+		sp = p.lspace(p.p)
+		if sp >= len(p.s) {
+			p.p = sp
+			return self.syntaxError(types.ERR_EOF, path)
+		}
+		switch p.s[sp] {
+		case ',':
+			p.p = sp + 1
+		case '}':
+			p.p = sp + 1
+			return ErrNotExist
+		default:
+			p.p = sp
+			return self.syntaxError(types.ERR_INVALID_CHAR, path)
+		}
+	}
+}
+
+// searchIndex advances the parser past an array's "[" up to element idx,
+// leaving self.parser.p positioned at the start of its value.
+func (self *Searcher) searchIndex(idx int, path []interface{}) error {
+	p := &self.parser
+	sp := p.lspace(p.p)
+	if sp >= len(p.s) {
+		p.p = sp
+		return self.syntaxError(types.ERR_EOF, path)
+	}
+	if p.s[sp] != '[' {
+		p.p = sp
+		return self.syntaxError(types.ERR_INVALID_CHAR, path)
+	}
+	p.p = sp + 1
+	sp = p.lspace(p.p)
+	if sp < len(p.s) && p.s[sp] == ']' {
+		p.p = sp + 1
+		return ErrNotExist
+	}
+	if idx < 0 {
+		return ErrNotExist
+	}
+	for i := 0; ; i++ {
+		p.p = p.lspace(p.p)
+		if i == idx {
+			return nil
+		}
+		if _, perr := p.skipFast(); perr != types.ERR_NONE {
+			return self.syntaxError(perr, path)
+		}
+		sp = p.lspace(p.p)
+		if sp >= len(p.s) {
+			p.p = sp
+			return self.syntaxError(types.ERR_EOF, path)
+		}
+		switch p.s[sp] {
+		case ',':
+			p.p = sp + 1
+		case ']':
+			p.p = sp + 1
+			return ErrNotExist
+		default:
+			p.p = sp
+			return self.syntaxError(types.ERR_INVALID_CHAR, path)
+		}
+	}
+}
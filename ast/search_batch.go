@@ -0,0 +1,301 @@
+/*
+ * Copyright 2021 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"github.com/ameer-alhadidi/sonic/internal/native/types"
+)
+
+// pathTrieNode groups the still-live paths that agree on every segment
+// resolved so far. leaves holds the index, into the caller's result slice,
+// of every path that terminates at this node; byKey/byIndex hold the
+// sub-tries to descend into for a given object member or array element.
+type pathTrieNode struct {
+	leaves  []int
+	byKey   map[string]*pathTrieNode
+	byIndex map[int]*pathTrieNode
+}
+
+func newPathTrieNode() *pathTrieNode {
+	return &pathTrieNode{}
+}
+
+func (self *pathTrieNode) child(seg interface{}) *pathTrieNode {
+	switch k := seg.(type) {
+	case string:
+		if self.byKey == nil {
+			self.byKey = make(map[string]*pathTrieNode)
+		}
+		c, ok := self.byKey[k]
+		if !ok {
+			c = newPathTrieNode()
+			self.byKey[k] = c
+		}
+		return c
+	case int:
+		if self.byIndex == nil {
+			self.byIndex = make(map[int]*pathTrieNode)
+		}
+		c, ok := self.byIndex[k]
+		if !ok {
+			c = newPathTrieNode()
+			self.byIndex[k] = c
+		}
+		return c
+	default:
+		panic("path segment must be either int or string")
+	}
+}
+
+// PathSet is a group of paths compiled into a single trie, so that
+// GetByPathSet can resolve all of them with one left-to-right scan of the
+// source JSON. A PathSet can be built once with CompilePathSet and reused
+// across many Searchers.
+type PathSet struct {
+	root *pathTrieNode
+	n    int
+}
+
+// CompilePathSet builds a PathSet out of paths, in the same []interface{}
+// form accepted by Searcher.GetByPath. The result of GetByPathSet mirrors
+// paths: result[i] corresponds to paths[i].
+func CompilePathSet(paths ...[]interface{}) *PathSet {
+	set := &PathSet{root: newPathTrieNode(), n: len(paths)}
+	for i, path := range paths {
+		node := set.root
+		for _, seg := range path {
+			node = node.child(seg)
+		}
+		node.leaves = append(node.leaves, i)
+	}
+	return set
+}
+
+// GetByPaths resolves many paths with a single left-to-right scan of the
+// source JSON, instead of restarting the parse once per path like a loop of
+// GetByPath would. It is a thin wrapper around CompilePathSet and
+// GetByPathSet for callers who don't need to reuse the compiled trie.
+//
+// It returns a Node and an error per requested path, in the same order as
+// paths; an unresolved path reports ErrNotExist in its slot like GetByPath
+// does, rather than failing every other path in the batch.
+func (self *Searcher) GetByPaths(paths ...[]interface{}) ([]Node, []error) {
+	return self.GetByPathSet(CompilePathSet(paths...))
+}
+
+// GetByPathSet resolves every path in set with a single left-to-right scan
+// of the source JSON. While descending into an object or array, the
+// searcher consults the trie and only recurses into members that at least
+// one live path still needs; every other member is skipped with the same
+// fast skip-value path GetByPath uses, so the cost is close to
+// O(input + sum of matched-subtree sizes) rather than O(input * len(paths)).
+//
+// GetByPathSet is safe to call with Searcher.ConcurrentRead set, same as
+// GetByPath.
+func (self *Searcher) GetByPathSet(set *PathSet) ([]Node, []error) {
+	nodes := make([]Node, set.n)
+	errs := make([]error, set.n)
+	for i := range errs {
+		errs[i] = ErrNotExist
+	}
+	self.parser.p = 0
+	self.scanTrie(set.root, nodes, errs)
+	return nodes, errs
+}
+
+// scanTrie consumes exactly one JSON value starting at the parser's current
+// position, resolving every leaf of node along the way, and leaves the
+// parser positioned just past that value.
+//
+// A path that still has segments left under node but lands on a scalar
+// here reports a *SyntaxError with the same Code and Offset GetByPath would
+// for descending into a non-container, rather than silently reporting
+// ErrNotExist; unlike GetByPath's, its Path is left empty.
+func (self *Searcher) scanTrie(node *pathTrieNode, nodes []Node, errs []error) {
+	p := &self.parser
+	sp := p.lspace(p.p)
+	if sp >= len(p.s) {
+		p.p = sp
+		self.failTrie(node, self.syntaxError(types.ERR_EOF, nil), errs)
+		return
+	}
+	start := sp
+	p.p = sp
+	switch p.s[sp] {
+	case '{':
+		self.scanObjectTrie(node, nodes, errs)
+	case '[':
+		self.scanArrayTrie(node, nodes, errs)
+	default:
+		if len(node.byKey) > 0 || len(node.byIndex) > 0 {
+			err := self.syntaxError(types.ERR_INVALID_CHAR, nil)
+			for _, c := range node.byKey {
+				self.failTrie(c, err, errs)
+			}
+			for _, c := range node.byIndex {
+				self.failTrie(c, err, errs)
+			}
+		}
+		if _, perr := p.skipFast(); perr != types.ERR_NONE {
+			self.failTrie(node, self.syntaxError(perr, nil), errs)
+			return
+		}
+	}
+	for _, i := range node.leaves {
+		if _, failed := errs[i].(*SyntaxError); failed {
+			// failTrie already reported a syntax error inside this value.
+			continue
+		}
+		nodes[i] = NewRaw(p.s[start:p.p])
+		errs[i] = nil
+	}
+}
+
+// skipTrie discards exactly one JSON value without resolving any path; it
+// is used for members that no live path needs.
+func (self *Searcher) skipTrie() types.ParsingError {
+	p := &self.parser
+	p.p = p.lspace(p.p)
+	_, perr := p.skipFast()
+	return perr
+}
+
+func (self *Searcher) scanObjectTrie(node *pathTrieNode, nodes []Node, errs []error) {
+	p := &self.parser
+	p.p++ // consume '{'
+	sp := p.lspace(p.p)
+	if sp >= len(p.s) {
+		p.p = sp
+		self.failTrie(node, self.syntaxError(types.ERR_EOF, nil), errs)
+		return
+	}
+	if p.s[sp] == '}' {
+		p.p = sp + 1
+		return
+	}
+	for {
+		sp = p.lspace(p.p)
+		if sp >= len(p.s) || p.s[sp] != '"' {
+			p.p = sp
+			self.failTrie(node, self.syntaxError(types.ERR_INVALID_CHAR, nil), errs)
+			return
+		}
+		key, perr := p.decodeString(sp)
+		if perr != types.ERR_NONE {
+			self.failTrie(node, self.syntaxError(perr, nil), errs)
+			return
+		}
+		sp = p.lspace(p.p)
+		if sp >= len(p.s) || p.s[sp] != ':' {
+			p.p = sp
+			self.failTrie(node, self.syntaxError(types.ERR_INVALID_CHAR, nil), errs)
+			return
+		}
+		p.p = sp + 1
+
+		var child *pathTrieNode
+		if node.byKey != nil {
+			child = node.byKey[key]
+		}
+		if child != nil {
+			self.scanTrie(child, nodes, errs)
+		} else if perr := self.skipTrie(); perr != types.ERR_NONE {
+			self.failTrie(node, self.syntaxError(perr, nil), errs)
+			return
+		}
+
+		sp = p.lspace(p.p)
+		if sp >= len(p.s) {
+			p.p = sp
+			self.failTrie(node, self.syntaxError(types.ERR_EOF, nil), errs)
+			return
+		}
+		switch p.s[sp] {
+		case ',':
+			p.p = sp + 1
+		case '}':
+			p.p = sp + 1
+			return
+		default:
+			p.p = sp
+			self.failTrie(node, self.syntaxError(types.ERR_INVALID_CHAR, nil), errs)
+			return
+		}
+	}
+}
+
+func (self *Searcher) scanArrayTrie(node *pathTrieNode, nodes []Node, errs []error) {
+	p := &self.parser
+	p.p++ // consume '['
+	sp := p.lspace(p.p)
+	if sp >= len(p.s) {
+		p.p = sp
+		self.failTrie(node, self.syntaxError(types.ERR_EOF, nil), errs)
+		return
+	}
+	if p.s[sp] == ']' {
+		p.p = sp + 1
+		return
+	}
+	for idx := 0; ; idx++ {
+		p.p = p.lspace(p.p)
+
+		var child *pathTrieNode
+		if node.byIndex != nil {
+			child = node.byIndex[idx]
+		}
+		if child != nil {
+			self.scanTrie(child, nodes, errs)
+		} else if perr := self.skipTrie(); perr != types.ERR_NONE {
+			self.failTrie(node, self.syntaxError(perr, nil), errs)
+			return
+		}
+
+		sp = p.lspace(p.p)
+		if sp >= len(p.s) {
+			p.p = sp
+			self.failTrie(node, self.syntaxError(types.ERR_EOF, nil), errs)
+			return
+		}
+		switch p.s[sp] {
+		case ',':
+			p.p = sp + 1
+		case ']':
+			p.p = sp + 1
+			return
+		default:
+			p.p = sp
+			self.failTrie(node, self.syntaxError(types.ERR_INVALID_CHAR, nil), errs)
+			return
+		}
+	}
+}
+
+// failTrie reports err on every leaf that is still live under node,
+// including its descendants, so one syntax error doesn't leave the rest of
+// the batch silently holding the zero-value ErrNotExist.
+func (self *Searcher) failTrie(node *pathTrieNode, err error, errs []error) {
+	for _, i := range node.leaves {
+		errs[i] = err
+	}
+	for _, c := range node.byKey {
+		self.failTrie(c, err, errs)
+	}
+	for _, c := range node.byIndex {
+		self.failTrie(c, err, errs)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2021 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearcher_GetByPaths(t *testing.T) {
+	s := NewSearcher(` { "xx" : [] ,"yy" :{ }, "test" : [ true , 0.1 , "abc", ["h"], {"a":"bc"} ] } `)
+
+	nodes, errs := s.GetByPaths(
+		[]interface{}{"test", 0},
+		[]interface{}{"test", 2},
+		[]interface{}{"test", 4, "a"},
+		[]interface{}{"nope"},
+	)
+	require.Len(t, nodes, 4)
+	require.Len(t, errs, 4)
+
+	require.NoError(t, errs[0])
+	b, _ := nodes[0].Bool()
+	assert.Equal(t, true, b)
+
+	require.NoError(t, errs[1])
+	str, _ := nodes[1].String()
+	assert.Equal(t, "abc", str)
+
+	require.NoError(t, errs[2])
+	str, _ = nodes[2].String()
+	assert.Equal(t, "bc", str)
+
+	assert.Equal(t, ErrNotExist, errs[3])
+	assert.False(t, nodes[3].Exists())
+}
+
+func TestSearcher_GetByPaths_SiblingKeys(t *testing.T) {
+	// Sibling keys at the same object level must resolve in one pass over
+	// that object's members, not one pass per path.
+	s := NewSearcher(`{"a":1,"b":2,"c":3,"d":4}`)
+	nodes, errs := s.GetByPaths(
+		[]interface{}{"d"},
+		[]interface{}{"b"},
+	)
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	a, _ := nodes[0].Int64()
+	assert.Equal(t, int64(4), a)
+	b, _ := nodes[1].Int64()
+	assert.Equal(t, int64(2), b)
+}
+
+func TestSearcher_GetByPaths_DescendIntoScalar(t *testing.T) {
+	// Descending past a scalar must report the same *SyntaxError kind
+	// GetByPath does, not ErrNotExist, for both a batch and a single path.
+	s := NewSearcher(`{"a":5}`)
+
+	_, err := s.GetByPath("a", "b")
+	var wantSyn *SyntaxError
+	require.ErrorAs(t, err, &wantSyn)
+
+	nodes, errs := NewSearcher(`{"a":5}`).GetByPaths([]interface{}{"a", "b"})
+	require.Len(t, errs, 1)
+	var gotSyn *SyntaxError
+	require.ErrorAs(t, errs[0], &gotSyn)
+	assert.False(t, nodes[0].Exists())
+}
+
+func BenchmarkGetByPaths_Sonic(b *testing.B) {
+	paths := [][]interface{}{
+		{"statuses", 0, "id"},
+		{"statuses", 1, "id"},
+		{"statuses", 2, "id"},
+		{"statuses", 3, "id"},
+		{"statuses", 3, "user", "screen_name"},
+	}
+	b.Run("GetByPaths", func(b *testing.B) {
+		b.SetBytes(int64(len(_TwitterJson)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			s := NewSearcher(_TwitterJson)
+			_, errs := s.GetByPaths(paths...)
+			for _, err := range errs {
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("LoopOfGetByPath", func(b *testing.B) {
+		b.SetBytes(int64(len(_TwitterJson)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			s := NewSearcher(_TwitterJson)
+			for _, path := range paths {
+				if _, err := s.GetByPath(path...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
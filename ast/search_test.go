@@ -18,10 +18,10 @@ package ast
 
 import (
 	"encoding/json"
+	"errors"
 	"math"
 	"runtime"
 	"strconv"
-	"strings"
 	"sync"
 	"testing"
 
@@ -130,29 +130,44 @@ func TestExportErrorInvalidChar(t *testing.T) {
 	if err == nil {
 		t.Fatal()
 	}
-	if strings.Index(err.Error(), `"Syntax error at `) != 0 {
+	var synErr *SyntaxError
+	if !errors.As(err, &synErr) {
 		t.Fatal(err)
 	}
+	if synErr.Code != ErrInvalidChar {
+		t.Fatal(synErr)
+	}
 
 	data = `:"b"]`
 	p = NewSearcher(data)
 	_, err = p.GetByPath("a")
-	if err == nil {
-		t.Fatal()
-	}
-	if err.Error() != `"Syntax error at index 0: invalid char\n\n\t:\"b\"]\n\t^....\n"` {
+	if !errors.As(err, &synErr) {
 		t.Fatal(err)
 	}
+	if synErr.Code != ErrInvalidChar || synErr.Offset != 0 {
+		t.Fatal(synErr)
+	}
+	if line, col := synErr.Position(); line != 1 || col != 1 {
+		t.Fatal(line, col)
+	}
 
 	data = `{:"b"]`
 	p = NewSearcher(data)
 	_, err = p.GetByPath("a")
-	if err == nil {
-		t.Fatal()
-	}
-	if err.Error() != `"Syntax error at index 1: invalid char\n\n\t{:\"b\"]\n\t.^....\n"` {
+	if !errors.As(err, &synErr) {
 		t.Fatal(err)
 	}
+	if synErr.Code != ErrInvalidChar || synErr.Offset != 1 {
+		t.Fatal(synErr)
+	}
+
+	// LegacySyntaxErrorFormat must still reproduce the old quoted,
+	// multi-line message byte-for-byte, for callers that match on it.
+	LegacySyntaxErrorFormat = true
+	if synErr.Error() != `"Syntax error at index 1: invalid char\n\n\t{:\"b\"]\n\t.^....\n"` {
+		t.Fatal(synErr.Error())
+	}
+	LegacySyntaxErrorFormat = false
 
 	data = `{`
 	p = NewSearcher(data)
@@ -160,7 +175,7 @@ func TestExportErrorInvalidChar(t *testing.T) {
 	if err == nil {
 		t.Fatal()
 	}
-	if err == ErrNotExist {
+	if errors.Is(err, ErrNotExist) {
 		t.Fatal(err)
 	}
 
@@ -170,9 +185,24 @@ func TestExportErrorInvalidChar(t *testing.T) {
 	if err == nil {
 		t.Fatal()
 	}
-	if err == ErrNotExist {
+	if errors.Is(err, ErrNotExist) {
+		t.Fatal(err)
+	}
+}
+
+func TestExportErrNotExist_PathContext(t *testing.T) {
+	// A not-exist error reached through GetByPath should still compare
+	// equal via errors.Is even when wrapped with the path that was being
+	// resolved, so existing callers that check `err == ErrNotExist` (or
+	// now errors.Is) keep working unchanged.
+	_, err := NewSearcher(`{"a":{"b":1}}`).GetByPath("a", "c")
+	if !errors.Is(err, ErrNotExist) {
 		t.Fatal(err)
 	}
+	wrapped := &PathError{Path: []interface{}{"a", "c"}, Err: ErrNotExist}
+	if !errors.Is(wrapped, ErrNotExist) {
+		t.Fatal(wrapped)
+	}
 }
 
 type testExportError struct {